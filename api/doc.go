@@ -0,0 +1,12 @@
+// Package api holds the HTTP types and server/client bindings for
+// openapi.yaml. generated.gen.go is meant to be produced by oapi-codegen
+// per codegen.yaml (models and the std-http-server only — client
+// generation is left off so it can't collide with client.gen.go); until
+// that can run here it's hand-written to match its output shape, so edit
+// it (and validate.go) directly when the spec changes. client.gen.go is
+// hand-maintained separately and isn't part of the go:generate recipe.
+// Run `go generate ./...` to refresh generated.gen.go for real once
+// oapi-codegen is available.
+package api
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen --config=codegen.yaml openapi.yaml
@@ -0,0 +1,151 @@
+// Package api provides primitives to interact with the openapi HTTP API.
+//
+// This file is hand-maintained to mirror the shape oapi-codegen would
+// produce from openapi.yaml (see doc.go's go:generate directive) and must
+// be kept in sync with the spec by hand until that command can actually
+// run in this environment. It is not generated output — edit it directly,
+// and update openapi.yaml and validate.go to match.
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Model defines model for Model.
+type Model string
+
+// Defines values for Model.
+const (
+	MetaLlamaLlama38bChatHf         Model = "meta-llama/Llama-3-8b-chat-hf"
+	MetaLlamaLlama370bChatHf        Model = "meta-llama/Llama-3-70b-chat-hf"
+	MistralaiMixtral8x7BInstructV01 Model = "mistralai/Mixtral-8x7B-Instruct-v0.1"
+)
+
+// ChatMessage defines model for ChatMessage.
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatCompletionRequest defines model for ChatCompletionRequest.
+type ChatCompletionRequest struct {
+	Model       Model         `json:"model"`
+	Messages    []ChatMessage `json:"messages"`
+	Stream      bool          `json:"stream,omitempty"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature float64       `json:"temperature,omitempty"`
+}
+
+// ChatCompletionResponse defines model for ChatCompletionResponse.
+type ChatCompletionResponse struct {
+	Model   string      `json:"model"`
+	Message ChatMessage `json:"message"`
+}
+
+// GenerateRequest defines model for GenerateRequest.
+type GenerateRequest struct {
+	Model       Model    `json:"model,omitempty"`
+	Prompt      string   `json:"prompt"`
+	Stream      bool     `json:"stream,omitempty"`
+	MaxTokens   int      `json:"max_tokens,omitempty"`
+	Temperature float64  `json:"temperature,omitempty"`
+	TopP        float64  `json:"top_p,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+}
+
+// GenerateResponse defines model for GenerateResponse.
+type GenerateResponse struct {
+	Model     string `json:"model"`
+	Response  string `json:"response"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// ModelsResponse defines model for ModelsResponse.
+type ModelsResponse struct {
+	Models []Model `json:"models"`
+}
+
+// HealthzResponse defines model for HealthzResponse.
+type HealthzResponse struct {
+	Status string `json:"status"`
+}
+
+// Error defines model for Error.
+type Error struct {
+	Error string `json:"error"`
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Generate a completion for a single prompt.
+	// (POST /generate)
+	Generate(w http.ResponseWriter, r *http.Request)
+	// Generate a chat completion from a multi-turn message history.
+	// (POST /chat/completions)
+	ChatCompletions(w http.ResponseWriter, r *http.Request)
+	// List the models this proxy accepts.
+	// (GET /models)
+	ListModels(w http.ResponseWriter, r *http.Request)
+	// Report whether the service is ready to accept traffic.
+	// (GET /healthz)
+	Healthz(w http.ResponseWriter, r *http.Request)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+func (siw *ServerInterfaceWrapper) generate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	siw.Handler.Generate(w, r)
+}
+
+func (siw *ServerInterfaceWrapper) chatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	siw.Handler.ChatCompletions(w, r)
+}
+
+func (siw *ServerInterfaceWrapper) listModels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	siw.Handler.ListModels(w, r)
+}
+
+func (siw *ServerInterfaceWrapper) healthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	siw.Handler.Healthz(w, r)
+}
+
+// RegisterHandlers adds each operation from ServerInterface to mux.
+func RegisterHandlers(mux *http.ServeMux, si ServerInterface) *http.ServeMux {
+	wrapper := ServerInterfaceWrapper{Handler: si}
+
+	mux.HandleFunc("/generate", wrapper.generate)
+	mux.HandleFunc("/chat/completions", wrapper.chatCompletions)
+	mux.HandleFunc("/models", wrapper.listModels)
+	mux.HandleFunc("/healthz", wrapper.healthz)
+
+	return mux
+}
+
+// WriteJSON is a small helper the generated handlers' callers use to encode
+// typed responses; kept here so generated and hand-written code share one
+// encoding path.
+func WriteJSON(w http.ResponseWriter, status int, v interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validModels is the Model enum from openapi.yaml. Real oapi-codegen
+// projects enforce this (and the numeric ranges below) via a generated
+// request-validator middleware built from the embedded spec; this package
+// is hand-maintained, so the checks are hand-written instead. They enforce
+// the enum and ranges openapi.yaml declares, with two differences: a zero
+// numeric field passes as omitted, and blank (whitespace-only) prompts and
+// empty message contents are rejected.
+var validModels = map[Model]bool{
+	MetaLlamaLlama38bChatHf:         true,
+	MetaLlamaLlama370bChatHf:        true,
+	MistralaiMixtral8x7BInstructV01: true,
+}
+
+// ValidateGenerateRequest checks req against the constraints GenerateRequest
+// declares in openapi.yaml. A zero-valued Model, MaxTokens, Temperature, or
+// TopP is treated as "omitted" (the caller defaults Model and MaxTokens
+// before calling Together and passes a zero Temperature through as 0), so
+// only explicit out-of-range values are rejected.
+func ValidateGenerateRequest(req GenerateRequest) error {
+	if strings.TrimSpace(req.Prompt) == "" {
+		return fmt.Errorf("prompt is required")
+	}
+	if req.Model != "" && !validModels[req.Model] {
+		return fmt.Errorf("model %q is not a supported value", req.Model)
+	}
+	if req.MaxTokens != 0 && (req.MaxTokens < 1 || req.MaxTokens > 4096) {
+		return fmt.Errorf("max_tokens must be between 1 and 4096")
+	}
+	if req.Temperature != 0 && (req.Temperature < 0 || req.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2")
+	}
+	if req.TopP != 0 && (req.TopP < 0 || req.TopP > 1) {
+		return fmt.Errorf("top_p must be between 0 and 1")
+	}
+	return nil
+}
+
+// ValidateChatRequest checks req against the constraints
+// ChatCompletionRequest declares in openapi.yaml.
+func ValidateChatRequest(req ChatCompletionRequest) error {
+	if req.Model == "" {
+		return fmt.Errorf("model is required")
+	}
+	if !validModels[req.Model] {
+		return fmt.Errorf("model %q is not a supported value", req.Model)
+	}
+	if len(req.Messages) == 0 {
+		return fmt.Errorf("messages must contain at least one entry")
+	}
+	for i, m := range req.Messages {
+		if m.Role != "system" && m.Role != "user" && m.Role != "assistant" {
+			return fmt.Errorf("messages[%d].role must be one of system, user, assistant", i)
+		}
+		if m.Content == "" {
+			return fmt.Errorf("messages[%d].content is required", i)
+		}
+	}
+	if req.MaxTokens != 0 && (req.MaxTokens < 1 || req.MaxTokens > 4096) {
+		return fmt.Errorf("max_tokens must be between 1 and 4096")
+	}
+	if req.Temperature != 0 && (req.Temperature < 0 || req.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2")
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package together
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const chatCompletionsPath = "/chat/completions"
+
+// ChatMessage is a single turn in a chat completion's message history.
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ChatRequest mirrors the subset of Together's /chat/completions request
+// body this client supports. Unlike CompletionRequest, it targets chat
+// models (e.g. Llama-3-8b-chat-hf) via multi-turn message history instead
+// of a single prompt string.
+type ChatRequest struct {
+	Model       string
+	Messages    []ChatMessage
+	MaxTokens   int
+	Temperature float64
+	Stream      bool
+}
+
+// ChatResponse is the result of a non-streaming Chat call.
+type ChatResponse struct {
+	Model   string
+	Message ChatMessage
+	Usage   Usage
+}
+
+// Chat sends a non-streaming chat completion request and returns the
+// assistant's reply.
+func (c *Client) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	resp, err := c.doChatRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Choices []struct {
+			Message ChatMessage `json:"message"`
+		} `json:"choices"`
+		Usage Usage `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("no choices returned")
+	}
+
+	return &ChatResponse{
+		Model:   req.Model,
+		Message: result.Choices[0].Message,
+		Usage:   result.Usage,
+	}, nil
+}
+
+// StreamChat sends a streaming chat completion request, invoking onDelta for
+// every assistant content delta emitted by the upstream SSE stream. It
+// returns the usage reported in the final chunk, if Together included one.
+func (c *Client) StreamChat(ctx context.Context, req ChatRequest, onDelta func(delta string) error) (Usage, error) {
+	req.Stream = true
+
+	var usage Usage
+
+	resp, err := c.doChatRequest(ctx, req)
+	if err != nil {
+		return usage, err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			data, ok = strings.CutPrefix(line, "data:")
+		}
+		if !ok {
+			continue
+		}
+
+		data = strings.TrimSpace(data)
+		if data == "" {
+			continue
+		}
+		if data == doneSentinel {
+			return usage, nil
+		}
+
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Usage *Usage `json:"usage"`
+		}
+
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			continue
+		}
+
+		if chunk.Usage != nil {
+			usage = *chunk.Usage
+		}
+
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		content := chunk.Choices[0].Delta.Content
+		if content == "" {
+			continue
+		}
+
+		if err := onDelta(content); err != nil {
+			return usage, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return usage, fmt.Errorf("stream read failed: %w", err)
+	}
+
+	return usage, nil
+}
+
+func (c *Client) doChatRequest(ctx context.Context, req ChatRequest) (*http.Response, error) {
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("API key not set")
+	}
+
+	body := map[string]interface{}{
+		"model":       req.Model,
+		"messages":    req.Messages,
+		"max_tokens":  req.MaxTokens,
+		"temperature": req.Temperature,
+		"stream":      req.Stream,
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	return c.doWithRetry(ctx, chatCompletionsPath, bodyBytes)
+}
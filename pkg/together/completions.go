@@ -0,0 +1,245 @@
+package together
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const completionsPath = "/completions"
+
+// CompletionRequest mirrors the subset of Together's /completions request
+// body this client supports.
+type CompletionRequest struct {
+	Model       string
+	Prompt      string
+	MaxTokens   int
+	Temperature float64
+	TopP        float64
+	Stop        []string
+	Stream      bool
+}
+
+// CompletionResponse is the result of a non-streaming Complete call.
+type CompletionResponse struct {
+	Model    string
+	Response string
+	Usage    Usage
+}
+
+// Usage reports Together's token accounting for a single request.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Complete sends a non-streaming completion request and returns the first
+// choice's text.
+func (c *Client) Complete(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
+	resp, err := c.doCompletionsRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Choices []struct {
+			Text string `json:"text"`
+		} `json:"choices"`
+		Usage Usage `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("no choices returned")
+	}
+
+	return &CompletionResponse{
+		Model:    req.Model,
+		Response: result.Choices[0].Text,
+		Usage:    result.Usage,
+	}, nil
+}
+
+const doneSentinel = "[DONE]"
+
+// StreamComplete sends a streaming completion request and parses the
+// upstream Server-Sent Events response (`data: {...}` frames terminated by
+// `data: [DONE]`), invoking onDelta for every token. onDelta is called
+// synchronously on the calling goroutine; returning an error from it aborts
+// the stream and is returned to the caller. The request is bound to ctx, so
+// cancelling ctx aborts the upstream call. It returns the usage reported in
+// the final chunk, if Together included one.
+func (c *Client) StreamComplete(ctx context.Context, req CompletionRequest, onDelta func(delta string) error) (Usage, error) {
+	req.Stream = true
+
+	var usage Usage
+
+	resp, err := c.doCompletionsRequest(ctx, req)
+	if err != nil {
+		return usage, err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			data, ok = strings.CutPrefix(line, "data:")
+		}
+		if !ok {
+			continue
+		}
+
+		data = strings.TrimSpace(data)
+		if data == "" {
+			continue
+		}
+		if data == doneSentinel {
+			return usage, nil
+		}
+
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Usage *Usage `json:"usage"`
+		}
+
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			continue
+		}
+
+		if chunk.Usage != nil {
+			usage = *chunk.Usage
+		}
+
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		content := chunk.Choices[0].Delta.Content
+		if content == "" {
+			continue
+		}
+
+		if err := onDelta(content); err != nil {
+			return usage, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return usage, fmt.Errorf("stream read failed: %w", err)
+	}
+
+	return usage, nil
+}
+
+func (c *Client) doCompletionsRequest(ctx context.Context, req CompletionRequest) (*http.Response, error) {
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("API key not set")
+	}
+
+	body := map[string]interface{}{
+		"model":       req.Model,
+		"prompt":      req.Prompt,
+		"max_tokens":  req.MaxTokens,
+		"temperature": req.Temperature,
+		"top_p":       req.TopP,
+		"stop":        req.Stop,
+		"stream":      req.Stream,
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	return c.doWithRetry(ctx, completionsPath, bodyBytes)
+}
+
+func (c *Client) doWithRetry(ctx context.Context, path string, bodyBytes []byte) (*http.Response, error) {
+	requestID, err := newRequestID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate request id: %w", err)
+	}
+
+	var lastErr error
+	delay := c.backoff
+
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleepOrDone(ctx, delay); err != nil {
+				return nil, err
+			}
+			delay *= 2
+		}
+
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(bodyBytes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("X-Request-ID", requestID)
+
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			lastErr = fmt.Errorf("request failed: %w", err)
+			continue
+		}
+
+		if resp.StatusCode >= 500 && attempt < c.maxRetries {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			lastErr = fmt.Errorf("upstream returned status %d", resp.StatusCode)
+			continue
+		}
+
+		if resp.StatusCode >= 400 {
+			defer resp.Body.Close()
+			payload, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("upstream returned status %d: %s", resp.StatusCode, payload)
+		}
+
+		return resp, nil
+	}
+
+	return nil, lastErr
+}
+
+func sleepOrDone(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func newRequestID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", b), nil
+}
@@ -0,0 +1,165 @@
+package together
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestComplete(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"choices":[{"text":"hello there"}],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`)
+	}))
+	defer srv.Close()
+
+	c := New(srv.Client(), "test-key", srv.URL)
+	resp, err := c.Complete(context.Background(), CompletionRequest{Model: "m", Prompt: "hi"})
+	if err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+	if resp.Response != "hello there" {
+		t.Errorf("Response = %q, want %q", resp.Response, "hello there")
+	}
+	if resp.Usage.TotalTokens != 5 {
+		t.Errorf("Usage.TotalTokens = %d, want 5", resp.Usage.TotalTokens)
+	}
+}
+
+func TestComplete_NoAPIKey(t *testing.T) {
+	c := New(http.DefaultClient, "", "https://example.invalid")
+	if _, err := c.Complete(context.Background(), CompletionRequest{Prompt: "hi"}); err == nil {
+		t.Fatal("expected an error when no API key is set")
+	}
+}
+
+func TestComplete_NoChoices(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"choices":[]}`)
+	}))
+	defer srv.Close()
+
+	c := New(srv.Client(), "test-key", srv.URL)
+	if _, err := c.Complete(context.Background(), CompletionRequest{Prompt: "hi"}); err == nil {
+		t.Fatal("expected an error when upstream returns no choices")
+	}
+}
+
+func TestStreamComplete(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"He\"}}]}\n\n")
+		flusher.Flush()
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"llo\"}}]}\n\n")
+		flusher.Flush()
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":1,\"completion_tokens\":2,\"total_tokens\":3}}\n\n")
+		flusher.Flush()
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		flusher.Flush()
+	}))
+	defer srv.Close()
+
+	c := New(srv.Client(), "test-key", srv.URL)
+
+	var deltas []string
+	usage, err := c.StreamComplete(context.Background(), CompletionRequest{Prompt: "hi"}, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamComplete returned error: %v", err)
+	}
+	if len(deltas) != 2 || deltas[0] != "He" || deltas[1] != "llo" {
+		t.Errorf("deltas = %v, want [He llo]", deltas)
+	}
+	if usage.TotalTokens != 3 {
+		t.Errorf("Usage.TotalTokens = %d, want 3", usage.TotalTokens)
+	}
+}
+
+func TestStreamComplete_OnDeltaErrorAborts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"He\"}}]}\n\n")
+		flusher.Flush()
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"llo\"}}]}\n\n")
+		flusher.Flush()
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		flusher.Flush()
+	}))
+	defer srv.Close()
+
+	c := New(srv.Client(), "test-key", srv.URL)
+
+	wantErr := fmt.Errorf("stop")
+	calls := 0
+	_, err := c.StreamComplete(context.Background(), CompletionRequest{Prompt: "hi"}, func(delta string) error {
+		calls++
+		return wantErr
+	})
+	if err != wantErr {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if calls != 1 {
+		t.Errorf("onDelta called %d times, want 1", calls)
+	}
+}
+
+func TestDoWithRetry_RetriesOn5xxThenSucceeds(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `{"choices":[{"text":"ok"}]}`)
+	}))
+	defer srv.Close()
+
+	c := New(srv.Client(), "test-key", srv.URL, WithMaxRetries(3), WithBackoff(time.Millisecond))
+	resp, err := c.Complete(context.Background(), CompletionRequest{Prompt: "hi"})
+	if err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+	if resp.Response != "ok" {
+		t.Errorf("Response = %q, want %q", resp.Response, "ok")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestDoWithRetry_GivesUpAfterMaxRetries(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := New(srv.Client(), "test-key", srv.URL, WithMaxRetries(2), WithBackoff(time.Millisecond))
+	if _, err := c.Complete(context.Background(), CompletionRequest{Prompt: "hi"}); err == nil {
+		t.Fatal("expected an error after exhausting retries")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("attempts = %d, want 3 (1 initial + 2 retries)", got)
+	}
+}
+
+func TestDoWithRetry_ContextCancellation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := New(srv.Client(), "test-key", srv.URL, WithMaxRetries(3), WithBackoff(time.Minute))
+	if _, err := c.Complete(ctx, CompletionRequest{Prompt: "hi"}); err == nil {
+		t.Fatal("expected an error when the context is already cancelled before a retry sleep")
+	}
+}
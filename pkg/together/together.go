@@ -0,0 +1,64 @@
+// Package together provides a small client for the Together AI completions API.
+package together
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultBaseURL is the Together AI API root used when no base URL is supplied.
+const DefaultBaseURL = "https://api.together.xyz/v1"
+
+const (
+	defaultMaxRetries = 3
+	defaultBackoff    = 250 * time.Millisecond
+)
+
+// Client talks to the Together AI API over a pluggable http.Client, so tests
+// can stub transports without touching the network.
+type Client struct {
+	httpClient *http.Client
+	apiKey     string
+	baseURL    string
+	maxRetries int
+	backoff    time.Duration
+}
+
+// Option configures optional Client behaviour.
+type Option func(*Client)
+
+// WithMaxRetries sets how many times a failed request is retried with
+// exponential backoff before giving up.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) { c.maxRetries = n }
+}
+
+// WithBackoff sets the base delay used between retries. Each retry doubles
+// the previous delay.
+func WithBackoff(d time.Duration) Option {
+	return func(c *Client) { c.backoff = d }
+}
+
+// New builds a Client. If httpClient is nil, http.DefaultClient is used.
+func New(httpClient *http.Client, apiKey, baseURL string, opts ...Option) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	c := &Client{
+		httpClient: httpClient,
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		maxRetries: defaultMaxRetries,
+		backoff:    defaultBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
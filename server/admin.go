@@ -0,0 +1,55 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+)
+
+// adminMiddleware gates admin-only endpoints behind a single shared token,
+// separate from the per-caller API keys used for /generate and
+// /chat/completions.
+func adminMiddleware(adminToken string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if adminToken == "" || !ok || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// usageHandler serves per-key usage aggregates from the configured
+// UsageStore.
+func usageHandler(store UsageStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		aggregates, err := store.Aggregates(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Usage []UsageAggregate `json:"usage"`
+		}{Usage: aggregates})
+	}
+}
+
+// cacheStatsHandler reports cumulative cache hit/miss counts.
+func cacheStatsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Hits   int64 `json:"hits"`
+		Misses int64 `json:"misses"`
+	}{Hits: cacheHits.Load(), Misses: cacheMisses.Load()})
+}
+
+// cachePurgeHandler clears every entry from the configured Cache.
+func cachePurgeHandler(cache Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cache.Purge()
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/volchok96/go-together-ai/api"
+)
+
+// bindChatRequest decodes an api.ChatCompletionRequest from the request
+// body. It supports JSON, XML, and form-urlencoded bodies based on
+// Content-Type, defaulting to JSON when the header is absent or unknown.
+func bindChatRequest(r *http.Request) (api.ChatCompletionRequest, error) {
+	var req api.ChatCompletionRequest
+
+	switch contentType(r.Header.Get("Content-Type")) {
+	case "application/xml", "text/xml":
+		if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+			return req, fmt.Errorf("invalid xml body: %w", err)
+		}
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return req, fmt.Errorf("invalid form body: %w", err)
+		}
+		req.Model = api.Model(r.PostForm.Get("model"))
+		req.Messages = []api.ChatMessage{{Role: "user", Content: r.PostForm.Get("message")}}
+		if v := r.PostForm.Get("max_tokens"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return req, fmt.Errorf("invalid max_tokens: %w", err)
+			}
+			req.MaxTokens = n
+		}
+		if v := r.PostForm.Get("temperature"); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return req, fmt.Errorf("invalid temperature: %w", err)
+			}
+			req.Temperature = f
+		}
+		if v := r.PostForm.Get("stream"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return req, fmt.Errorf("invalid stream: %w", err)
+			}
+			req.Stream = b
+		}
+	default:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return req, fmt.Errorf("invalid json body: %w", err)
+		}
+	}
+
+	return req, nil
+}
+
+// writeResponse encodes v as JSON or XML depending on the client's Accept
+// header, defaulting to JSON when the header is absent, "*/*", or
+// unrecognized.
+func writeResponse(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	switch contentType(firstAccept(r.Header.Get("Accept"))) {
+	case "application/xml", "text/xml":
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(status)
+		xml.NewEncoder(w).Encode(v)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(v)
+	}
+}
+
+func firstAccept(accept string) string {
+	if accept == "" {
+		return ""
+	}
+	return strings.TrimSpace(strings.Split(accept, ",")[0])
+}
+
+func contentType(header string) string {
+	mt, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return "application/json"
+	}
+	return mt
+}
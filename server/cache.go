@@ -0,0 +1,153 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache stores serialized generation responses keyed by a content-addressed
+// fingerprint of the request that produced them.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Put(key string, value []byte, ttl time.Duration)
+	Purge()
+}
+
+// cacheHits and cacheMisses back the /cache/stats endpoint.
+var (
+	cacheHits   atomic.Int64
+	cacheMisses atomic.Int64
+)
+
+// cacheKey fingerprints the fields that determine a completion's output.
+// Two requests that differ only in, say, stream framing hash identically,
+// so a streamed and non-streamed call for the same prompt share a cache
+// entry.
+func cacheKey(model, prompt string, maxTokens int, temperature, topP float64, stop []string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%d\x00%g\x00%g\x00%s", model, prompt, maxTokens, temperature, topP, strings.Join(stop, "\x1f"))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// cacheableRequest reports whether a request is safe to serve from cache: only
+// deterministic (temperature == 0) requests qualify, unless the caller
+// opts in with X-Cache: force.
+func cacheableRequest(temperature float64, forceHeader string) bool {
+	return temperature == 0 || forceHeader == "force"
+}
+
+// lruEntry is one cached value plus its absolute expiry.
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// LRUCache is an in-memory, size-bounded Cache. It's the default backend.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+// NewLRUCache builds an LRUCache holding up to capacity entries.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		cacheMisses.Add(1)
+		return nil, false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		cacheMisses.Add(1)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	cacheHits.Add(1)
+	return entry.value, true
+}
+
+func (c *LRUCache) Put(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		el.Value.(*lruEntry).value = value
+		el.Value.(*lruEntry).expires = time.Now().Add(ttl)
+		return
+	}
+
+	el := c.ll.PushFront(&lruEntry{key: key, value: value, expires: time.Now().Add(ttl)})
+	c.items[key] = el
+
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruEntry).key)
+		}
+	}
+}
+
+func (c *LRUCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+}
+
+// RedisCache is a Cache backend for sharing cached responses across proxy
+// replicas.
+type RedisCache struct {
+	client *redis.Client
+}
+
+// NewRedisCache builds a RedisCache against an already-configured client.
+func NewRedisCache(client *redis.Client) *RedisCache {
+	return &RedisCache{client: client}
+}
+
+func (c *RedisCache) Get(key string) ([]byte, bool) {
+	value, err := c.client.Get(context.Background(), key).Bytes()
+	if err != nil {
+		cacheMisses.Add(1)
+		return nil, false
+	}
+	cacheHits.Add(1)
+	return value, true
+}
+
+func (c *RedisCache) Put(key string, value []byte, ttl time.Duration) {
+	c.client.Set(context.Background(), key, value, ttl)
+}
+
+func (c *RedisCache) Purge() {
+	c.client.FlushDB(context.Background())
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheKey_StableAndDistinguishesFields(t *testing.T) {
+	base := cacheKey("m", "prompt", 10, 0, 0, nil)
+	if got := cacheKey("m", "prompt", 10, 0, 0, nil); got != base {
+		t.Errorf("cacheKey is not deterministic: %q != %q", got, base)
+	}
+	if got := cacheKey("other-model", "prompt", 10, 0, 0, nil); got == base {
+		t.Error("cacheKey ignored model")
+	}
+	if got := cacheKey("m", "other prompt", 10, 0, 0, nil); got == base {
+		t.Error("cacheKey ignored prompt")
+	}
+	if got := cacheKey("m", "prompt", 20, 0, 0, nil); got == base {
+		t.Error("cacheKey ignored maxTokens")
+	}
+	if got := cacheKey("m", "prompt", 10, 0.5, 0, nil); got == base {
+		t.Error("cacheKey ignored temperature")
+	}
+	if got := cacheKey("m", "prompt", 10, 0, 0.9, nil); got == base {
+		t.Error("cacheKey ignored topP")
+	}
+	if got := cacheKey("m", "prompt", 10, 0, 0, []string{"stop"}); got == base {
+		t.Error("cacheKey ignored stop")
+	}
+}
+
+func TestCacheableRequest(t *testing.T) {
+	cases := []struct {
+		name        string
+		temperature float64
+		forceHeader string
+		want        bool
+	}{
+		{"zero temperature is cacheable", 0, "", true},
+		{"nonzero temperature is not cacheable", 0.7, "", false},
+		{"nonzero temperature forced", 0.7, "force", true},
+		{"zero temperature with unrelated header", 0, "bogus", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := cacheableRequest(tc.temperature, tc.forceHeader); got != tc.want {
+				t.Errorf("cacheableRequest(%v, %q) = %v, want %v", tc.temperature, tc.forceHeader, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLRUCache_GetPut(t *testing.T) {
+	c := NewLRUCache(2)
+
+	if _, ok := c.Get("missing"); ok {
+		t.Fatal("Get on empty cache should miss")
+	}
+
+	c.Put("a", []byte("1"), time.Minute)
+	v, ok := c.Get("a")
+	if !ok || string(v) != "1" {
+		t.Fatalf("Get(a) = %q, %v, want 1, true", v, ok)
+	}
+}
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRUCache(2)
+
+	c.Put("a", []byte("1"), time.Minute)
+	c.Put("b", []byte("2"), time.Minute)
+	c.Get("a") // touch a so b is the least recently used
+	c.Put("c", []byte("3"), time.Minute)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b should have been evicted as least recently used")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Error("a should still be cached")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Error("c should be cached")
+	}
+}
+
+func TestLRUCache_ExpiresByTTL(t *testing.T) {
+	c := NewLRUCache(2)
+
+	c.Put("a", []byte("1"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("expired entry should not be returned")
+	}
+}
+
+func TestLRUCache_Purge(t *testing.T) {
+	c := NewLRUCache(2)
+	c.Put("a", []byte("1"), time.Minute)
+	c.Purge()
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("Purge should remove all entries")
+	}
+}
+
+func TestCacheEntry_TokensOrWhole(t *testing.T) {
+	withTokens := cacheEntry{Response: "ignored", Tokens: []string{"a", "b"}}
+	if got := withTokens.tokensOrWhole(); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("tokensOrWhole() = %v, want [a b]", got)
+	}
+
+	wholeOnly := cacheEntry{Response: "whole"}
+	if got := wholeOnly.tokensOrWhole(); len(got) != 1 || got[0] != "whole" {
+		t.Errorf("tokensOrWhole() = %v, want [whole]", got)
+	}
+}
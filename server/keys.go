@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+type contextKey string
+
+const keyIDContextKey contextKey = "keyID"
+
+// apiKey is one caller's credentials: a bearer token for simple auth and,
+// optionally, an HMAC secret for signed requests.
+type apiKey struct {
+	ID         string
+	Token      string
+	HMACSecret []byte
+}
+
+// KeySet holds the configured caller credentials, indexed for fast lookup
+// by either auth scheme.
+type KeySet struct {
+	byToken map[string]apiKey
+	byID    map[string]apiKey
+}
+
+// LoadKeySet reads caller credentials from a file, one key per line in the
+// form "id:token:hmac_secret" (hmac_secret may be empty). Blank lines and
+// lines starting with '#' are ignored. If path is empty, an empty KeySet is
+// returned and every request is rejected.
+func LoadKeySet(path string) (*KeySet, error) {
+	ks := &KeySet{byToken: map[string]apiKey{}, byID: map[string]apiKey{}}
+	if path == "" {
+		return ks, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid key line %q: want id:token[:hmac_secret]", line)
+		}
+
+		key := apiKey{ID: parts[0], Token: parts[1]}
+		if len(parts) == 3 && parts[2] != "" {
+			key.HMACSecret = []byte(parts[2])
+		}
+
+		ks.byID[key.ID] = key
+		if key.Token != "" {
+			ks.byToken[key.Token] = key
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	return ks, nil
+}
+
+// Authenticate validates r against either a bearer token in the
+// Authorization header or an HMAC-SHA256 signature of the request body in
+// the X-Signature header (paired with an X-Api-Key-Id header identifying
+// which caller's secret to verify against). It returns the matching key ID.
+func (ks *KeySet) Authenticate(r *http.Request) (string, bool) {
+	if token, ok := bearerToken(r); ok {
+		key, ok := ks.byToken[token]
+		if !ok {
+			return "", false
+		}
+		return key.ID, true
+	}
+
+	keyID := r.Header.Get("X-Api-Key-Id")
+	sig := r.Header.Get("X-Signature")
+	if keyID == "" || sig == "" {
+		return "", false
+	}
+
+	key, ok := ks.byID[keyID]
+	if !ok || len(key.HMACSecret) == 0 {
+		return "", false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", false
+	}
+	r.Body = io.NopCloser(strings.NewReader(string(body)))
+
+	mac := hmac.New(sha256.New, key.HMACSecret)
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(sig)) != 1 {
+		return "", false
+	}
+
+	return key.ID, true
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	auth := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(auth, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(auth, prefix), true
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeKeyFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+	return path
+}
+
+func TestLoadKeySet_EmptyPathRejectsEverything(t *testing.T) {
+	ks, err := LoadKeySet("")
+	if err != nil {
+		t.Fatalf("LoadKeySet(\"\") returned error: %v", err)
+	}
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer anything")
+	if _, ok := ks.Authenticate(r); ok {
+		t.Error("an empty KeySet should reject every request")
+	}
+}
+
+func TestLoadKeySet_ParsesLines(t *testing.T) {
+	path := writeKeyFile(t, "# comment\n\nalice:secret-token\nbob:bob-token:bob-hmac-secret\n")
+
+	ks, err := LoadKeySet(path)
+	if err != nil {
+		t.Fatalf("LoadKeySet returned error: %v", err)
+	}
+
+	if _, ok := ks.byToken["secret-token"]; !ok {
+		t.Error("alice's bearer token was not indexed")
+	}
+	bob, ok := ks.byID["bob"]
+	if !ok || string(bob.HMACSecret) != "bob-hmac-secret" {
+		t.Errorf("bob's HMAC secret = %q, ok=%v, want bob-hmac-secret, true", bob.HMACSecret, ok)
+	}
+}
+
+func TestLoadKeySet_RejectsMalformedLine(t *testing.T) {
+	path := writeKeyFile(t, "justoneword\n")
+	if _, err := LoadKeySet(path); err == nil {
+		t.Fatal("expected an error for a line without a token")
+	}
+}
+
+func TestKeySet_AuthenticateBearerToken(t *testing.T) {
+	path := writeKeyFile(t, "alice:secret-token\n")
+	ks, err := LoadKeySet(path)
+	if err != nil {
+		t.Fatalf("LoadKeySet returned error: %v", err)
+	}
+
+	r, _ := http.NewRequest(http.MethodPost, "/generate", nil)
+	r.Header.Set("Authorization", "Bearer secret-token")
+	keyID, ok := ks.Authenticate(r)
+	if !ok || keyID != "alice" {
+		t.Errorf("Authenticate = %q, %v, want alice, true", keyID, ok)
+	}
+
+	r.Header.Set("Authorization", "Bearer wrong-token")
+	if _, ok := ks.Authenticate(r); ok {
+		t.Error("Authenticate should reject an unknown bearer token")
+	}
+}
+
+func TestKeySet_AuthenticateHMAC(t *testing.T) {
+	path := writeKeyFile(t, "bob:bob-token:bob-hmac-secret\n")
+	ks, err := LoadKeySet(path)
+	if err != nil {
+		t.Fatalf("LoadKeySet returned error: %v", err)
+	}
+
+	body := "the request body"
+	mac := hmac.New(sha256.New, []byte("bob-hmac-secret"))
+	mac.Write([]byte(body))
+	sig := hex.EncodeToString(mac.Sum(nil))
+
+	r, _ := http.NewRequest(http.MethodPost, "/generate", strings.NewReader(body))
+	r.Header.Set("X-Api-Key-Id", "bob")
+	r.Header.Set("X-Signature", sig)
+
+	keyID, ok := ks.Authenticate(r)
+	if !ok || keyID != "bob" {
+		t.Errorf("Authenticate = %q, %v, want bob, true", keyID, ok)
+	}
+}
+
+func TestKeySet_AuthenticateHMACRejectsBadSignature(t *testing.T) {
+	path := writeKeyFile(t, "bob:bob-token:bob-hmac-secret\n")
+	ks, err := LoadKeySet(path)
+	if err != nil {
+		t.Fatalf("LoadKeySet returned error: %v", err)
+	}
+
+	r, _ := http.NewRequest(http.MethodPost, "/generate", strings.NewReader("the request body"))
+	r.Header.Set("X-Api-Key-Id", "bob")
+	r.Header.Set("X-Signature", "0000")
+
+	if _, ok := ks.Authenticate(r); ok {
+		t.Error("Authenticate should reject a bad HMAC signature")
+	}
+}
+
+func TestKeySet_AuthenticateRejectsNoCredentials(t *testing.T) {
+	path := writeKeyFile(t, "alice:secret-token\n")
+	ks, err := LoadKeySet(path)
+	if err != nil {
+		t.Fatalf("LoadKeySet returned error: %v", err)
+	}
+
+	r, _ := http.NewRequest(http.MethodPost, "/generate", nil)
+	if _, ok := ks.Authenticate(r); ok {
+		t.Error("Authenticate should reject a request with no bearer token or signature")
+	}
+}
@@ -1,16 +1,26 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/volchok96/go-together-ai/api"
+	"github.com/volchok96/go-together-ai/pkg/together"
 )
 
+const heartbeatInterval = 15 * time.Second
+
+// GenerationRequest is the JSON body accepted by /ws/generate. It mirrors
+// api.GenerateRequest; the WebSocket surface predates the OpenAPI spec and
+// isn't part of the generated contract.
 type GenerationRequest struct {
 	Model       string  `json:"model"`
 	Prompt      string  `json:"prompt"`
@@ -19,127 +29,343 @@ type GenerationRequest struct {
 	Temperature float64 `json:"temperature"`
 }
 
-type GenerationResponse struct {
-	Model     string `json:"model"`
-	Response  string `json:"response"`
-	CreatedAt string `json:"created_at,omitempty"`
-}
+var (
+	apiClient  *together.Client
+	keySet     *KeySet
+	limiters   *rateLimiters
+	usageStore UsageStore
+	adminToken string
 
-const TogetherAPIURL = "https://api.together.xyz/v1/completions"
+	respCache         Cache
+	cacheTTL          time.Duration
+	cacheReplayPacing time.Duration
+)
 
-func generateHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
+var supportedModels = []api.Model{
+	api.MetaLlamaLlama38bChatHf,
+	api.MetaLlamaLlama370bChatHf,
+	api.MistralaiMixtral8x7BInstructV01,
+}
 
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
+// apiServer implements api.ServerInterface, which api/generated.gen.go
+// declares to mirror api/openapi.yaml.
+type apiServer struct{}
 
-	var req GenerationRequest
+func (apiServer) Generate(w http.ResponseWriter, r *http.Request) {
+	var req api.GenerateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		api.WriteJSON(w, http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+	if err := api.ValidateGenerateRequest(req); err != nil {
+		api.WriteJSON(w, http.StatusBadRequest, api.Error{Error: err.Error()})
 		return
 	}
 
 	if req.Model == "" {
-		req.Model = "meta-llama/Llama-3-8b-chat-hf"
+		req.Model = api.MetaLlamaLlama38bChatHf
 	}
 	if req.MaxTokens == 0 {
 		req.MaxTokens = 512
 	}
-	if req.Temperature == 0 {
-		req.Temperature = 0.1
+	// Temperature is deliberately left at its submitted value (0 when
+	// omitted) rather than defaulted: cacheableRequest treats temperature
+	// 0 as deterministic, so the key, the cached entry, and the upstream
+	// call all have to agree on that same temperature 0, or a single
+	// sampled response would get frozen and replayed as if it were
+	// deterministic.
+	key := cacheKey(string(req.Model), req.Prompt, req.MaxTokens, req.Temperature, req.TopP, req.Stop)
+	useCache := respCache != nil && cacheableRequest(req.Temperature, r.Header.Get("X-Cache"))
+
+	if useCache {
+		if stored, ok := respCache.Get(key); ok {
+			var entry cacheEntry
+			if err := json.Unmarshal(stored, &entry); err == nil {
+				if req.Stream {
+					replayCachedStream(w, r, entry.tokensOrWhole())
+				} else {
+					api.WriteJSON(w, http.StatusOK, api.GenerateResponse{Model: string(req.Model), Response: entry.Response})
+				}
+				return
+			}
+		}
+	}
+
+	completionReq := together.CompletionRequest{
+		Model:       string(req.Model),
+		Prompt:      req.Prompt,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		Stop:        req.Stop,
+		Stream:      req.Stream,
+	}
+
+	if req.Stream {
+		cacheKeyForStream := ""
+		if useCache {
+			cacheKeyForStream = key
+		}
+		streamSSE(w, r, "generate", cacheKeyForStream, func(ctx context.Context, onDelta func(string) error) (together.Usage, error) {
+			return apiClient.StreamComplete(ctx, completionReq, onDelta)
+		})
+		return
 	}
 
-	err := queryExternalAPI(req, w)
+	start := time.Now()
+	resp, err := apiClient.Complete(r.Context(), completionReq)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		api.WriteJSON(w, http.StatusInternalServerError, api.Error{Error: err.Error()})
+		return
+	}
+	upstreamLatencySeconds.WithLabelValues("generate").Observe(time.Since(start).Seconds())
+	recordUsage(r.Context(), "generate", resp.Usage, start)
+
+	if useCache {
+		if b, err := json.Marshal(cacheEntry{Response: resp.Response}); err == nil {
+			respCache.Put(key, b, cacheTTL)
+		}
 	}
+
+	api.WriteJSON(w, http.StatusOK, api.GenerateResponse{
+		Model:    resp.Model,
+		Response: resp.Response,
+	})
 }
 
-func queryExternalAPI(req GenerationRequest, w http.ResponseWriter) error {
-	apiKey := os.Getenv("TOGETHER_API_KEY")
-	if apiKey == "" {
-		return fmt.Errorf("API key not set in TOGETHER_API_KEY environment variable")
+func (apiServer) ChatCompletions(w http.ResponseWriter, r *http.Request) {
+	req, err := bindChatRequest(r)
+	if err != nil {
+		writeResponse(w, r, http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
+	}
+	// model and messages are required by ChatCompletionRequest in
+	// openapi.yaml, so unlike Generate's prompt-only requirement, an
+	// absent model is a validation error rather than something to default.
+	if err := api.ValidateChatRequest(req); err != nil {
+		writeResponse(w, r, http.StatusBadRequest, api.Error{Error: err.Error()})
+		return
 	}
 
-	requestBody := map[string]interface{}{
-		"model":       req.Model,
-		"prompt":      req.Prompt,
-		"max_tokens":  req.MaxTokens,
-		"temperature": req.Temperature,
-		"stream":      req.Stream,
+	if req.MaxTokens == 0 {
+		req.MaxTokens = 512
+	}
+	if req.Temperature == 0 {
+		req.Temperature = 0.1
 	}
 
-	bodyBytes, err := json.Marshal(requestBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %v", err)
+	messages := make([]together.ChatMessage, len(req.Messages))
+	for i, m := range req.Messages {
+		messages[i] = together.ChatMessage{Role: m.Role, Content: m.Content}
 	}
 
-	httpReq, err := http.NewRequest("POST", TogetherAPIURL, bytes.NewBuffer(bodyBytes))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
+	chatReq := together.ChatRequest{
+		Model:       string(req.Model),
+		Messages:    messages,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		Stream:      req.Stream,
 	}
 
-	httpReq.Header.Set("Authorization", "Bearer "+apiKey)
-	httpReq.Header.Set("Content-Type", "application/json")
+	if req.Stream {
+		streamSSE(w, r, "chat", "", func(ctx context.Context, onDelta func(string) error) (together.Usage, error) {
+			return apiClient.StreamChat(ctx, chatReq, onDelta)
+		})
+		return
+	}
 
-	resp, err := http.DefaultClient.Do(httpReq)
+	start := time.Now()
+	resp, err := apiClient.Chat(r.Context(), chatReq)
 	if err != nil {
-		return fmt.Errorf("request failed: %v", err)
+		writeResponse(w, r, http.StatusInternalServerError, api.Error{Error: err.Error()})
+		return
 	}
-	defer resp.Body.Close()
+	upstreamLatencySeconds.WithLabelValues("chat").Observe(time.Since(start).Seconds())
+	recordUsage(r.Context(), "chat", resp.Usage, start)
 
-	if req.Stream {
-		w.Header().Set("Content-Type", "text/event-stream")
-		w.Header().Set("Cache-Control", "no-cache")
-		w.Header().Set("Connection", "keep-alive")
-
-		decoder := json.NewDecoder(resp.Body)
-		flusher, _ := w.(http.Flusher)
-
-		for decoder.More() {
-			var chunk struct {
-				Choices []struct {
-					Delta struct {
-						Content string `json:"content"`
-					} `json:"delta"`
-				} `json:"choices"`
-			}
+	writeResponse(w, r, http.StatusOK, api.ChatCompletionResponse{
+		Model: resp.Model,
+		Message: api.ChatMessage{
+			Role:    resp.Message.Role,
+			Content: resp.Message.Content,
+		},
+	})
+}
+
+func (apiServer) ListModels(w http.ResponseWriter, r *http.Request) {
+	api.WriteJSON(w, http.StatusOK, api.ModelsResponse{Models: supportedModels})
+}
+
+func (apiServer) Healthz(w http.ResponseWriter, r *http.Request) {
+	api.WriteJSON(w, http.StatusOK, api.HealthzResponse{Status: "ok"})
+}
+
+// corsMiddleware allows any origin, matching the permissive CORS the proxy
+// has always exposed on /generate.
+func corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		next.ServeHTTP(w, r)
+	})
+}
 
-			if err := decoder.Decode(&chunk); err != nil {
-				break
+// streamSSE proxies a streaming completion as Server-Sent Events: a
+// `event: token` frame per delta, a heartbeat comment every
+// heartbeatInterval to keep intermediate proxies from closing the
+// connection, and a terminal `event: done` frame. The upstream request is
+// bound to the client's request context, so a client disconnect cancels it.
+// start is called with a delta callback and should run one of the
+// together.Client streaming methods. endpoint labels the usage/latency
+// accounting recorded once the stream finishes. If cacheKeyStr is
+// non-empty, the full set of deltas is cached under that key once the
+// stream completes successfully.
+func streamSSE(w http.ResponseWriter, r *http.Request, endpoint, cacheKeyStr string, start func(ctx context.Context, onDelta func(string) error) (together.Usage, error)) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := r.Context()
+	startTime := time.Now()
+	tokens := make(chan string)
+	done := make(chan error, 1)
+	var usage together.Usage
+	var collected []string
+
+	go func() {
+		var err error
+		usage, err = start(ctx, func(delta string) error {
+			if cacheKeyStr != "" {
+				collected = append(collected, delta)
+			}
+			select {
+			case tokens <- delta:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
 			}
+		})
+		close(tokens)
+		done <- err
+	}()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
 
-			if len(chunk.Choices) > 0 {
-				content := chunk.Choices[0].Delta.Content
-				if content != "" {
-					fmt.Fprintf(w, "%s", content)
-					flusher.Flush()
+	for {
+		select {
+		case delta, open := <-tokens:
+			if !open {
+				err := <-done
+				if err != nil {
+					fmt.Fprintf(w, "event: error\ndata: %s\n\n", mustJSON(map[string]string{"error": err.Error()}))
+				} else {
+					fmt.Fprintf(w, "event: done\ndata: {}\n\n")
+					if cacheKeyStr != "" {
+						if b, merr := json.Marshal(cacheEntry{Response: strings.Join(collected, ""), Tokens: collected}); merr == nil {
+							respCache.Put(cacheKeyStr, b, cacheTTL)
+						}
+					}
 				}
+				flusher.Flush()
+				upstreamLatencySeconds.WithLabelValues(endpoint).Observe(time.Since(startTime).Seconds())
+				recordUsage(r.Context(), endpoint, usage, startTime)
+				return
 			}
+			fmt.Fprintf(w, "event: token\ndata: %s\n\n", mustJSON(map[string]string{"text": delta}))
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		case <-ctx.Done():
+			return
 		}
-		return nil
 	}
+}
+
+// cacheEntry is the value stored for a completion cache key. Tokens is only
+// populated when the entry was captured from a streaming response; replay
+// falls back to treating Response as a single chunk otherwise.
+type cacheEntry struct {
+	Response string   `json:"response"`
+	Tokens   []string `json:"tokens,omitempty"`
+}
 
-	var result struct {
-		Choices []struct {
-			Text string `json:"text"`
-		} `json:"choices"`
+func (e cacheEntry) tokensOrWhole() []string {
+	if len(e.Tokens) > 0 {
+		return e.Tokens
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return fmt.Errorf("failed to decode response: %v", err)
+	return []string{e.Response}
+}
+
+// replayCachedStream re-emits a cached completion as the same SSE framing a
+// live stream would produce, pacing frames by cacheReplayPacing so
+// downstream consumers see comparable timing.
+func replayCachedStream(w http.ResponseWriter, r *http.Request, tokens []string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
 	}
 
-	if len(result.Choices) == 0 {
-		return fmt.Errorf("no choices returned")
+	ctx := r.Context()
+	for _, tok := range tokens {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		fmt.Fprintf(w, "event: token\ndata: %s\n\n", mustJSON(map[string]string{"text": tok}))
+		flusher.Flush()
+
+		if cacheReplayPacing > 0 {
+			time.Sleep(cacheReplayPacing)
+		}
 	}
 
-	respJSON := GenerationResponse{
-		Model:    req.Model,
-		Response: result.Choices[0].Text,
+	fmt.Fprint(w, "event: done\ndata: {}\n\n")
+	flusher.Flush()
+}
+
+// recordUsage persists request accounting for the caller identified in
+// ctx (set by gatewayMiddleware) and updates the matching token counters.
+func recordUsage(ctx context.Context, endpoint string, usage together.Usage, start time.Time) {
+	keyID, _ := ctx.Value(keyIDContextKey).(string)
+
+	if usageStore != nil {
+		rec := UsageRecord{
+			KeyID:            keyID,
+			Endpoint:         endpoint,
+			PromptTokens:     usage.PromptTokens,
+			CompletionTokens: usage.CompletionTokens,
+			LatencyMS:        time.Since(start).Milliseconds(),
+			Timestamp:        start,
+		}
+		if err := usageStore.Record(ctx, rec); err != nil {
+			log.Printf("failed to record usage: %v", err)
+		}
 	}
-	return json.NewEncoder(w).Encode(respJSON)
+
+	tokensTotal.WithLabelValues(keyID, "prompt").Add(float64(usage.PromptTokens))
+	tokensTotal.WithLabelValues(keyID, "completion").Add(float64(usage.CompletionTokens))
+}
+
+func mustJSON(v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return []byte("{}")
+	}
+	return b
 }
 
 func main() {
@@ -148,13 +374,71 @@ func main() {
 		log.Println("No .env file found. Using environment variables.")
 	}
 
+	apiClient = together.New(http.DefaultClient, os.Getenv("TOGETHER_API_KEY"), together.DefaultBaseURL)
+
+	var err error
+	keySet, err = LoadKeySet(os.Getenv("TOGETHER_PROXY_KEYS_FILE"))
+	if err != nil {
+		log.Fatalf("failed to load API keys: %v", err)
+	}
+
+	limiters = newRateLimiters(envFloat("TOGETHER_PROXY_RPS", 2), envInt("TOGETHER_PROXY_BURST", 10))
+	adminToken = os.Getenv("TOGETHER_PROXY_ADMIN_TOKEN")
+
+	usageDBPath := os.Getenv("TOGETHER_PROXY_USAGE_DB")
+	if usageDBPath == "" {
+		usageDBPath = "usage.db"
+	}
+	store, err := NewSQLiteUsageStore(usageDBPath)
+	if err != nil {
+		log.Fatalf("failed to open usage store: %v", err)
+	}
+	defer store.Close()
+	usageStore = store
+
+	respCache = NewLRUCache(envInt("TOGETHER_CACHE_SIZE", 1000))
+	cacheTTL = time.Duration(envInt("TOGETHER_CACHE_TTL_SECONDS", 600)) * time.Second
+	cacheReplayPacing = time.Duration(envInt("TOGETHER_CACHE_REPLAY_PACING_MS", 20)) * time.Millisecond
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	http.HandleFunc("/generate", generateHandler)
+	mux := http.NewServeMux()
+	api.RegisterHandlers(mux, apiServer{})
+	mux.HandleFunc("/ws/generate", wsGenerateHandler)
+	mux.HandleFunc("/metrics", metricsHandler())
+	mux.HandleFunc("/usage", adminMiddleware(adminToken, usageHandler(usageStore)))
+	mux.HandleFunc("/cache/stats", adminMiddleware(adminToken, cacheStatsHandler))
+	mux.HandleFunc("/cache/purge", adminMiddleware(adminToken, cachePurgeHandler(respCache)))
+
+	handler := corsMiddleware(metricsMiddleware(gatewayMiddleware(mux)))
 
 	log.Printf("Server running on http://localhost:%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	log.Fatal(http.ListenAndServe(":"+port, handler))
+}
+
+func envFloat(name string, fallback float64) float64 {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fallback
+	}
+	return f
+}
+
+func envInt(name string, fallback int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fallback
+	}
+	return n
 }
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "together_proxy_http_requests_total",
+		Help: "Total HTTP requests handled, by path and status.",
+	}, []string{"path", "status"})
+
+	upstreamLatencySeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "together_proxy_upstream_latency_seconds",
+		Help:    "Latency of upstream Together API calls, by endpoint.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint"})
+
+	tokensTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "together_proxy_tokens_total",
+		Help: "Tokens accounted per API key, by kind (prompt/completion).",
+	}, []string{"key_id", "kind"})
+)
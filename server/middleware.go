@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// protectedPaths are the endpoints gatewayMiddleware requires a valid API
+// key (and rate limit headroom) for. Everything else — /models, /healthz,
+// /metrics, /usage — has its own access control (admin token) or none,
+// since it doesn't reach the paid upstream.
+var protectedPaths = map[string]bool{
+	"/generate":         true,
+	"/chat/completions": true,
+	"/ws/generate":      true,
+}
+
+// gatewayMiddleware authenticates and rate-limits requests to
+// protectedPaths, storing the resolved key ID in the request context for
+// downstream usage accounting. Other paths pass through untouched.
+func gatewayMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !protectedPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		keyID, ok := keySet.Authenticate(r)
+		if !ok {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if !limiters.Allow(keyID, r.RemoteAddr) {
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), keyIDContextKey, keyID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// statusRecorder captures the status code a handler writes so
+// metricsMiddleware can label the request counter with it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Flush delegates to the embedded ResponseWriter so SSE handlers behind
+// metricsMiddleware can still flush incrementally.
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack delegates to the embedded ResponseWriter so the WebSocket upgrade
+// in wsGenerateHandler can still take over the connection.
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("underlying ResponseWriter does not support hijacking")
+	}
+	return hj.Hijack()
+}
+
+// metricsMiddleware records a request count per path/status for every
+// request, regardless of whether it hit a protected endpoint.
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		httpRequestsTotal.WithLabelValues(r.URL.Path, strconv.Itoa(rec.status)).Inc()
+	})
+}
+
+// metricsHandler exposes Prometheus metrics for scraping.
+func metricsHandler() http.HandlerFunc {
+	h := promhttp.Handler()
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r)
+	}
+}
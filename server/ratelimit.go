@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimiters enforces independent token-bucket limits per API key and per
+// client IP, so one caller sharing a key from many hosts (or one host
+// hammering with many keys) is still bounded.
+type rateLimiters struct {
+	rps   float64
+	burst int
+
+	mu    sync.Mutex
+	byKey map[string]*rate.Limiter
+	byIP  map[string]*rate.Limiter
+}
+
+// newRateLimiters builds limiters that allow rps requests per second per
+// key/IP, with bursts up to burst requests.
+func newRateLimiters(rps float64, burst int) *rateLimiters {
+	return &rateLimiters{
+		rps:   rps,
+		burst: burst,
+		byKey: map[string]*rate.Limiter{},
+		byIP:  map[string]*rate.Limiter{},
+	}
+}
+
+// Allow reports whether a request from keyID and remoteAddr is within both
+// the per-key and per-IP limits.
+func (rl *rateLimiters) Allow(keyID, remoteAddr string) bool {
+	ip := remoteAddr
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		ip = host
+	}
+
+	return rl.limiterFor(rl.byKey, keyID).Allow() && rl.limiterFor(rl.byIP, ip).Allow()
+}
+
+func (rl *rateLimiters) limiterFor(m map[string]*rate.Limiter, id string) *rate.Limiter {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	limiter, ok := m[id]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(rl.rps), rl.burst)
+		m[id] = limiter
+	}
+	return limiter
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// UsageRecord is one accounted request against the proxy.
+type UsageRecord struct {
+	KeyID            string
+	Endpoint         string
+	PromptTokens     int
+	CompletionTokens int
+	LatencyMS        int64
+	Timestamp        time.Time
+}
+
+// UsageAggregate summarizes accounted usage for one API key.
+type UsageAggregate struct {
+	KeyID            string `json:"key_id"`
+	Requests         int64  `json:"requests"`
+	PromptTokens     int64  `json:"prompt_tokens"`
+	CompletionTokens int64  `json:"completion_tokens"`
+}
+
+// UsageStore persists per-key request accounting. Implementations must be
+// safe for concurrent use.
+type UsageStore interface {
+	Record(ctx context.Context, rec UsageRecord) error
+	Aggregates(ctx context.Context) ([]UsageAggregate, error)
+	Close() error
+}
+
+// SQLiteUsageStore is the default UsageStore backend.
+type SQLiteUsageStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteUsageStore opens (creating if necessary) a SQLite database at
+// path and ensures its schema exists.
+func NewSQLiteUsageStore(path string) (*SQLiteUsageStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open usage database: %w", err)
+	}
+
+	const schema = `
+		CREATE TABLE IF NOT EXISTS usage_records (
+			key_id            TEXT NOT NULL,
+			endpoint          TEXT NOT NULL,
+			prompt_tokens     INTEGER NOT NULL,
+			completion_tokens INTEGER NOT NULL,
+			latency_ms        INTEGER NOT NULL,
+			ts                DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_usage_records_key_id ON usage_records(key_id);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create usage schema: %w", err)
+	}
+
+	return &SQLiteUsageStore{db: db}, nil
+}
+
+// Record inserts one usage row.
+func (s *SQLiteUsageStore) Record(ctx context.Context, rec UsageRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO usage_records (key_id, endpoint, prompt_tokens, completion_tokens, latency_ms, ts)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		rec.KeyID, rec.Endpoint, rec.PromptTokens, rec.CompletionTokens, rec.LatencyMS, rec.Timestamp,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	return nil
+}
+
+// Aggregates returns per-key request counts and token totals.
+func (s *SQLiteUsageStore) Aggregates(ctx context.Context) ([]UsageAggregate, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT key_id, COUNT(*), SUM(prompt_tokens), SUM(completion_tokens)
+		FROM usage_records
+		GROUP BY key_id
+		ORDER BY key_id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+	defer rows.Close()
+
+	var aggregates []UsageAggregate
+	for rows.Next() {
+		var a UsageAggregate
+		if err := rows.Scan(&a.KeyID, &a.Requests, &a.PromptTokens, &a.CompletionTokens); err != nil {
+			return nil, fmt.Errorf("failed to scan usage row: %w", err)
+		}
+		aggregates = append(aggregates, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage rows: %w", err)
+	}
+
+	return aggregates, nil
+}
+
+// Close releases the underlying database handle.
+func (s *SQLiteUsageStore) Close() error {
+	return s.db.Close()
+}
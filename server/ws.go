@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/volchok96/go-together-ai/pkg/together"
+)
+
+const (
+	maxConcurrentWSConns = 32
+	wsPingInterval       = 30 * time.Second
+	wsPongWait           = 60 * time.Second
+)
+
+var (
+	wsUpgrader = websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+	// wsConnSlots bounds how many /ws/generate connections can be in flight
+	// at once; acquiring a slot is a non-blocking send so callers over the
+	// limit are rejected instead of queued.
+	wsConnSlots = make(chan struct{}, maxConcurrentWSConns)
+)
+
+type wsTokenMessage struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+type wsDoneMessage struct {
+	Type  string         `json:"type"`
+	Usage together.Usage `json:"usage"`
+}
+
+type wsErrorMessage struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+type wsControlMessage struct {
+	Action string `json:"action"`
+}
+
+// wsGenerateHandler accepts a GenerationRequest over a WebSocket connection
+// and streams token/done messages back. A client may send
+// {"action":"cancel"} at any point to abort the in-flight Together request.
+func wsGenerateHandler(w http.ResponseWriter, r *http.Request) {
+	select {
+	case wsConnSlots <- struct{}{}:
+		defer func() { <-wsConnSlots }()
+	default:
+		http.Error(w, "too many concurrent connections", http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("ws upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		return nil
+	})
+
+	var req GenerationRequest
+	if err := conn.ReadJSON(&req); err != nil {
+		conn.WriteJSON(wsErrorMessage{Type: "error", Error: "invalid request: " + err.Error()})
+		return
+	}
+
+	if req.Model == "" {
+		req.Model = "meta-llama/Llama-3-8b-chat-hf"
+	}
+	if req.MaxTokens == 0 {
+		req.MaxTokens = 512
+	}
+	if req.Temperature == 0 {
+		req.Temperature = 0.1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var writeMu sync.Mutex
+	go readWSControlMessages(conn, cancel)
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+	go func() {
+		for {
+			select {
+			case <-ping.C:
+				writeMu.Lock()
+				err := conn.WriteMessage(websocket.PingMessage, nil)
+				writeMu.Unlock()
+				if err != nil {
+					cancel()
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	usage, err := apiClient.StreamComplete(ctx, together.CompletionRequest{
+		Model:       req.Model,
+		Prompt:      req.Prompt,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+	}, func(delta string) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteJSON(wsTokenMessage{Type: "token", Text: delta})
+	})
+
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		writeMu.Lock()
+		conn.WriteJSON(wsErrorMessage{Type: "error", Error: err.Error()})
+		writeMu.Unlock()
+		return
+	}
+
+	writeMu.Lock()
+	conn.WriteJSON(wsDoneMessage{Type: "done", Usage: usage})
+	writeMu.Unlock()
+}
+
+// readWSControlMessages reads client-sent frames for the lifetime of the
+// connection and cancels the in-flight request on a {"action":"cancel"}
+// message or on any read error (including the client closing the socket).
+func readWSControlMessages(conn *websocket.Conn, cancel context.CancelFunc) {
+	for {
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			cancel()
+			return
+		}
+
+		var ctrl wsControlMessage
+		if err := json.Unmarshal(payload, &ctrl); err != nil {
+			continue
+		}
+		if ctrl.Action == "cancel" {
+			cancel()
+			return
+		}
+	}
+}